@@ -0,0 +1,314 @@
+// Package rotate provides a size- and time-based rotating file for use as
+// the logFile passed to xlog.Init. Writes are queued and applied by a
+// background goroutine so that callers never block on disk I/O.
+package rotate
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const defaultQueueSize = 1024
+
+// Config configures a RotatingFile.
+type Config struct {
+	// Filename is the path of the active log segment. Rotated segments are
+	// written alongside it with a timestamp suffix, e.g.
+	// "app.log" -> "app-20060102T150405.log".
+	Filename string
+
+	// MaxSize is the segment size, in bytes, above which a write triggers
+	// rotation. Zero disables size-based rotation.
+	MaxSize int64
+
+	// MaxAge is the age above which a rotated segment is deleted on the next
+	// rotation. Zero disables age-based cleanup.
+	MaxAge time.Duration
+
+	// MaxBackups is the number of rotated segments to retain; the oldest
+	// beyond this count are deleted on the next rotation. Zero means
+	// unlimited.
+	MaxBackups int
+
+	// Compress gzips a segment once it has been rotated out.
+	Compress bool
+
+	// Daily rotates the active segment at the next local midnight, in
+	// addition to any size-based rotation.
+	Daily bool
+
+	// QueueSize bounds the number of pending writes buffered for the
+	// background writer. Zero uses a default of 1024. Once full, further
+	// writes are dropped and counted in Stats.
+	QueueSize int
+}
+
+// Stats reports counters for a RotatingFile's background writer.
+type Stats struct {
+	Written uint64 // writes applied to disk
+	Dropped uint64 // writes discarded because the queue was full
+}
+
+// RotatingFile is an io.WriteCloser that rotates its underlying file by
+// size or daily at local midnight, optionally gzipping rotated segments and
+// pruning them by age and count. Writes are handed to a bounded queue serviced by a background
+// goroutine; Write never blocks on disk I/O.
+type RotatingFile struct {
+	cfg Config
+
+	queue chan []byte
+	done  chan struct{}
+	wg    sync.WaitGroup
+
+	closeMu sync.Mutex
+	closed  bool
+
+	written uint64
+	dropped uint64
+
+	// Owned by the background goroutine only.
+	file        *os.File
+	size        int64
+	nextDailyAt time.Time
+}
+
+// New creates a RotatingFile per cfg and starts its background writer.
+func New(cfg Config) (*RotatingFile, error) {
+	if cfg.Filename == "" {
+		return nil, fmt.Errorf("rotate: Config.Filename is required")
+	}
+	if cfg.QueueSize == 0 {
+		cfg.QueueSize = defaultQueueSize
+	}
+
+	r := &RotatingFile{
+		cfg:   cfg,
+		queue: make(chan []byte, cfg.QueueSize),
+		done:  make(chan struct{}),
+	}
+	if err := r.openCurrent(); err != nil {
+		return nil, err
+	}
+
+	r.wg.Add(1)
+	go r.run()
+	return r, nil
+}
+
+// Write queues p for the background writer and reports len(p), nil even
+// when the background write later fails (such failures are printed to
+// stderr). If the queue is full the
+// write is dropped and counted in Stats; logging must never block a caller
+// on disk I/O. Write returns os.ErrClosed once Close has been called.
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.closeMu.Lock()
+	defer r.closeMu.Unlock()
+	if r.closed {
+		return 0, os.ErrClosed
+	}
+
+	buf := append([]byte(nil), p...)
+	select {
+	case r.queue <- buf:
+	default:
+		atomic.AddUint64(&r.dropped, 1)
+	}
+	return len(p), nil
+}
+
+// Stats returns the current write/drop counters.
+func (r *RotatingFile) Stats() Stats {
+	return Stats{
+		Written: atomic.LoadUint64(&r.written),
+		Dropped: atomic.LoadUint64(&r.dropped),
+	}
+}
+
+// Close flushes any queued writes and closes the current segment. Writes
+// after Close return os.ErrClosed instead of sending on the closed queue.
+func (r *RotatingFile) Close() error {
+	r.closeMu.Lock()
+	r.closed = true
+	close(r.queue)
+	r.closeMu.Unlock()
+
+	r.wg.Wait()
+	if r.file != nil {
+		return r.file.Close()
+	}
+	return nil
+}
+
+func (r *RotatingFile) run() {
+	defer r.wg.Done()
+	for buf := range r.queue {
+		if err := r.writeSegment(buf); err != nil {
+			fmt.Fprintf(os.Stderr, "rotate: write failed: %v\n", err)
+			continue
+		}
+		atomic.AddUint64(&r.written, 1)
+	}
+}
+
+func (r *RotatingFile) writeSegment(buf []byte) error {
+	if r.shouldRotate(int64(len(buf))) {
+		if err := r.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := r.file.Write(buf)
+	r.size += int64(n)
+	return err
+}
+
+func (r *RotatingFile) shouldRotate(next int64) bool {
+	if r.cfg.MaxSize > 0 && r.size+next > r.cfg.MaxSize {
+		return true
+	}
+	if r.cfg.Daily && !r.nextDailyAt.IsZero() && !time.Now().Before(r.nextDailyAt) {
+		return true
+	}
+	return false
+}
+
+func (r *RotatingFile) openCurrent() error {
+	if err := os.MkdirAll(filepath.Dir(r.cfg.Filename), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(r.cfg.Filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	r.file = f
+	r.size = info.Size()
+	r.nextDailyAt = nextMidnight(time.Now())
+	return nil
+}
+
+// rotate closes the current segment, renames it aside with a timestamp,
+// reopens Filename, and prunes old segments per MaxAge/MaxBackups.
+func (r *RotatingFile) rotate() error {
+	if r.file != nil {
+		if err := r.file.Close(); err != nil {
+			return err
+		}
+	}
+
+	if r.size > 0 {
+		rotated := backupName(r.cfg.Filename, time.Now())
+		if err := os.Rename(r.cfg.Filename, rotated); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		if r.cfg.Compress {
+			if err := gzipFile(rotated); err != nil {
+				fmt.Fprintf(os.Stderr, "rotate: compress %s: %v\n", rotated, err)
+			}
+		}
+	}
+
+	r.prune()
+	return r.openCurrent()
+}
+
+func backupName(filename string, t time.Time) string {
+	ext := filepath.Ext(filename)
+	base := strings.TrimSuffix(filename, ext)
+	return fmt.Sprintf("%s-%s%s", base, t.Format("20060102T150405"), ext)
+}
+
+func gzipFile(name string) error {
+	src, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.Create(name + ".gz")
+	if err != nil {
+		return err
+	}
+	gw := gzip.NewWriter(dst)
+	if _, err := io.Copy(gw, src); err != nil {
+		gw.Close()
+		dst.Close()
+		return err
+	}
+	if err := gw.Close(); err != nil {
+		dst.Close()
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+	return os.Remove(name)
+}
+
+// prune deletes rotated segments of Filename older than MaxAge and, beyond
+// MaxBackups, the oldest remaining ones. Either limit may be zero to
+// disable that check.
+func (r *RotatingFile) prune() {
+	if r.cfg.MaxAge <= 0 && r.cfg.MaxBackups <= 0 {
+		return
+	}
+
+	dir := filepath.Dir(r.cfg.Filename)
+	ext := filepath.Ext(r.cfg.Filename)
+	base := strings.TrimSuffix(filepath.Base(r.cfg.Filename), ext)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	type backup struct {
+		path string
+		mod  time.Time
+	}
+	var backups []backup
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		name := e.Name()
+		if !strings.HasPrefix(name, base+"-") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backup{path: filepath.Join(dir, name), mod: info.ModTime()})
+	}
+
+	sort.Slice(backups, func(i, j int) bool { return backups[i].mod.After(backups[j].mod) })
+
+	now := time.Now()
+	var kept int
+	for _, b := range backups {
+		tooOld := r.cfg.MaxAge > 0 && now.Sub(b.mod) > r.cfg.MaxAge
+		tooMany := r.cfg.MaxBackups > 0 && kept >= r.cfg.MaxBackups
+		if tooOld || tooMany {
+			os.Remove(b.path)
+			continue
+		}
+		kept++
+	}
+}
+
+func nextMidnight(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d+1, 0, 0, 0, 0, t.Location())
+}
@@ -7,120 +7,126 @@ package xlog
 import (
 	"fmt"
 	"io"
-	"log"
 	"os"
+	"runtime"
+	"strings"
 	"sync"
+	"time"
 )
 
-type log_level int
+// Level identifies the severity of a log record, from most (LevelFatal) to
+// least (LevelTrace) severe.
+type Level int
 
 // Logging levels.
 const (
-	eFatal log_level = iota
+	eFatal Level = iota
 	eError
 	eWarn
 	eInfo
 	eTrace
 )
 
-// Logging tags.
+// Exported names for the logging levels, for use with AddBackend and
+// SetLevel.
 const (
-	tagFatal    	= "[F] "
-	tagError 	= "[E] "
-	tagWarn   	= "[W] "
-	tagInfo   	= "[I] "
-	tagTrace	= "[T] "
+	LevelFatal = eFatal
+	LevelError = eError
+	LevelWarn  = eWarn
+	LevelInfo  = eInfo
+	LevelTrace = eTrace
 )
 
+// Logging tags.
 const (
-	flags    = log.Ldate | log.Lmicroseconds | log.Lshortfile
-	initText = "Logging before logger.Init.\n"
+	tagFatal 	= "[F] "
+	tagError 	= "[E] "
+	tagWarn  	= "[W] "
+	tagInfo  	= "[I] "
+	tagTrace 	= "[T] "
 )
 
+const initText = "Logging before logger.Init.\n"
+
 var (
 	logLock       sync.Mutex
 	defaultLogger *Logger
 )
 
 func initialize() {
-	defaultLogger = &Logger{
-		logFatal:   	log.New(os.Stderr, initText+tagFatal, flags),
-		logError:   	log.New(os.Stderr, initText+tagError, flags),
-		logWarn: 	log.New(os.Stderr, initText+tagWarn, flags),
-		logInfo:    	log.New(os.Stderr, initText+tagInfo, flags),
-		logTrace: 	log.New(os.Stderr, initText+tagTrace, flags),
-	}
+	defaultLogger = &Logger{mu: &sync.RWMutex{}}
+	defaultLogger.addBackend("stderr", eTrace, newWriterBackend(os.Stderr, initText))
 }
 
 /**
  * 初始化默认Logger实例。
- * 
+ *
  * @return {[type]} [description]
  */
 func init() {
 	initialize()
 }
 
+// InitOptions carries settings for InitWithOptions beyond Init's required
+// positional parameters.
+type InitOptions struct {
+	// EventMessageFile is the path to a Windows message-resource DLL to
+	// register as the event source's message file, in place of the generic
+	// EventCreate message file InitAsEventCreate would otherwise use. This
+	// lets Event Viewer render a proper description for IDs passed to
+	// InfoID/WarningID/ErrorID instead of "The description for Event ID
+	// cannot be found". Ignored on non-Windows platforms.
+	EventMessageFile string
+}
+
 // Init sets up logging and should be called before log functions, usually in
 // the caller's main(). Default log functions can be called before Init(), but log
 // output will only go to stderr (along with a warning).
 // The first call to Init populates the default logger and returns the
 // generated logger, subsequent calls to Init will only return the generated
 // logger.
-// If the logFile passed in also satisfies io.Closer, logFile.Close will be called
-// when closing the logger.
+// If the logFile passed in also satisfies io.Closer, it will be closed when
+// the "file" backend is removed or the logger is closed.
+// Init is a convenience wrapper around the lower-level AddBackend API; it
+// wires up the "file" backend for logFile, a "syslog" backend (the Event Log
+// on Windows, syslog elsewhere) when systemLog is true, a "stderr" backend
+// for Error and Fatal records, and a "stdout" backend when verbose is true.
 func Init(name string, verbose, systemLog bool, logFile io.Writer) *Logger {
-	var il, wl, el io.Writer
-	var syslogErr error
+	return InitWithOptions(name, verbose, systemLog, logFile, InitOptions{})
+}
+
+// InitWithOptions acts like Init but additionally accepts InitOptions for
+// platform-specific configuration, such as a Windows message-resource DLL.
+func InitWithOptions(name string, verbose, systemLog bool, logFile io.Writer, opts InitOptions) *Logger {
+	l := &Logger{mu: &sync.RWMutex{}, initialized: true}
+
+	l.addBackend("file", eTrace, newWriterBackend(logFile, ""))
+
 	if systemLog {
-		il, wl, el, syslogErr = setup(name)
+		b, err := newSystemBackend(name, opts)
+		if err != nil {
+			Error(err)
+		} else {
+			l.addBackend("syslog", eTrace, b)
+		}
 	}
 
-	iLogs := []io.Writer{logFile}
-	wLogs := []io.Writer{logFile}
-	eLogs := []io.Writer{logFile}
-	if il != nil {
-		iLogs = append(iLogs, il)
-	}
-	if wl != nil {
-		wLogs = append(wLogs, wl)
-	}
-	if el != nil {
-		eLogs = append(eLogs, el)
-	}
-	// Windows services don't have stdout/stderr. Writes will fail, so try them last.
-	eLogs = append(eLogs, os.Stderr)
+	// Windows services don't have stdout/stderr, so these backends are
+	// best-effort and failures to write to them are not fatal. Error and
+	// Fatal records always reach stderr, matching past behavior; verbose
+	// additionally echoes every record to stdout, Error and Fatal included.
+	l.addBackend("stderr", eError, newWriterBackend(os.Stderr, ""))
 	if verbose {
-		iLogs = append(iLogs, os.Stdout)
-		wLogs = append(wLogs, os.Stdout)
-	}
-
-	l := Logger{
-		logFatal:   	log.New(io.MultiWriter(eLogs...), tagFatal, flags),
-		logError:   	log.New(io.MultiWriter(eLogs...), tagError, flags),
-		logWarn: 	log.New(io.MultiWriter(wLogs...), tagWarn, flags),
-		logInfo:    	log.New(io.MultiWriter(iLogs...), tagInfo, flags),
-		logTrace:    	log.New(io.MultiWriter(iLogs...), tagTrace, flags),
-		
-	}
-	for _, w := range []io.Writer{logFile, il, wl, el} {
-		if c, ok := w.(io.Closer); ok && c != nil {
-			l.closers = append(l.closers, c)
-		}
+		l.addBackend("stdout", eTrace, newWriterBackend(os.Stdout, ""))
 	}
-	l.initialized = true
 
 	logLock.Lock()
 	defer logLock.Unlock()
 	if !defaultLogger.initialized {
-		defaultLogger = &l
-	}
-
-	if syslogErr != nil {
-		Error(syslogErr)
+		defaultLogger = l
 	}
 
-	return &l
+	return l
 }
 
 // Close closes the default logger.
@@ -131,48 +137,102 @@ func Close() {
 // A Logger represents an active logging object. Multiple loggers can be used
 // simultaneously even if they are using the same same writers.
 type Logger struct {
-	logFatal    	*log.Logger
-	logError    	*log.Logger
-	logWarn  	*log.Logger
-	logInfo     	*log.Logger
-	logTrace    	*log.Logger
-	closers     	[]io.Closer
-	initialized 	bool
+	mu          *sync.RWMutex
+	backends    []backendEntry
+	initialized bool
+	name        	string
+	values      	[]interface{}
+
+	onFatal   func()
+	exitFunc  func(int)
+	stackAll  bool
+}
+
+// clone returns a new Logger sharing l's mutex, backends, name and values,
+// for use by WithValues/WithName. It reads l's fields under l's read lock.
+// The clone keeps l's *sync.RWMutex rather than getting its
+// own, so the two Loggers are still guarding the same backend slice with the
+// same lock: AddBackend/RemoveBackend/SetLevel calls on either Logger stay
+// mutually exclusive.
+func (l *Logger) clone() *Logger {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return &Logger{
+		mu:          l.mu,
+		backends:    l.backends,
+		initialized: l.initialized,
+		name:        l.name,
+		values:      l.values,
+		onFatal:     l.onFatal,
+		exitFunc:    l.exitFunc,
+		stackAll:    l.stackAll,
+	}
 }
 
-func (l *Logger) output(level log_level, depth int, txt string) {
-	logLock.Lock()
-	defer logLock.Unlock()
-	switch level {
-	case eTrace:
-		l.logTrace.Output(3+depth, txt)
-	case eInfo:
-		l.logInfo.Output(3+depth, txt)
-	case eWarn:
-		l.logWarn.Output(3+depth, txt)
-	case eError:
-		l.logError.Output(3+depth, txt)
-	case eFatal:
-		l.logFatal.Output(3+depth, txt)
-	default:
-		panic(fmt.Sprintln("unrecognized log_level:", level))
+func (l *Logger) output(level Level, depth int, txt string) {
+	_, file, line, ok := runtime.Caller(2 + depth)
+	if !ok {
+		file, line = "???", 0
 	}
+	l.record(level, file, line, 0, txt)
 }
 
-// Close closes all the underlying log writers, which will flush any cached logs.
-// Any errors from closing the underlying log writers will be printed to stderr.
+// outputID acts like output but threads id down to the Record, for backends
+// that key off a per-record event ID (currently the Windows Event Log
+// backend; id is ignored elsewhere).
+func (l *Logger) outputID(level Level, depth int, id uint32, txt string) {
+	_, file, line, ok := runtime.Caller(2 + depth)
+	if !ok {
+		file, line = "???", 0
+	}
+	l.record(level, file, line, id, txt)
+}
+
+func (l *Logger) record(level Level, file string, line int, id uint32, txt string) {
+	if !strings.HasSuffix(txt, "\n") {
+		txt += "\n"
+	}
+	rec := Record{Level: level, Time: time.Now(), File: file, Line: line, Message: txt, EventID: id}
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	for _, e := range l.backends {
+		if rec.Level > e.minLevel {
+			continue
+		}
+		if err := e.backend.Write(rec); err != nil {
+			fmt.Fprintf(os.Stderr, "xlog: backend %q: %v\n", e.name, err)
+		}
+	}
+}
+
+// Close closes all the underlying backends, which will flush any cached logs.
+// Any errors from closing the underlying backends will be printed to stderr.
 // Once Close is called, all future calls to the logger will panic.
 func (l *Logger) Close() {
-	logLock.Lock()
-	defer logLock.Unlock()
+	l.mu.Lock()
+	defer l.mu.Unlock()
 
 	if !l.initialized {
 		return
 	}
 
-	for _, c := range l.closers {
-		if err := c.Close(); err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to close log %v: %v\n", c, err)
+	for _, e := range l.backends {
+		if err := e.backend.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to close backend %q: %v\n", e.name, err)
+		}
+	}
+}
+
+// SetFlags sets the output flags (as in the standard log package, e.g.
+// log.Ldate|log.Lshortfile) on any of the default logger's backends that
+// support it, namely the built-in writer-based backends.
+func SetFlags(flag int) {
+	defaultLogger.mu.RLock()
+	defer defaultLogger.mu.RUnlock()
+	for _, e := range defaultLogger.backends {
+		if fs, ok := e.backend.(interface{ SetFlags(int) }); ok {
+			fs.SetFlags(flag)
 		}
 	}
 }
@@ -180,267 +240,241 @@ func (l *Logger) Close() {
 // Trace logs with the eTrace log_level.
 // Arguments are handled in the manner of fmt.Print.
 func (l *Logger) Trace(v ...interface{}) {
-	l.output(eTrace, 0, fmt.Sprint(v...))
+	l.output(eTrace, 0, fmt.Sprint(redactArgs(v)...))
 }
 
 // TraceDepth acts as Trace but uses depth to determine which call frame to log.
 // TraceDepth(0, "msg") is the same as Trace("msg").
 func (l *Logger) TraceDepth(depth int, v ...interface{}) {
-	l.output(eTrace, depth, fmt.Sprint(v...))
+	l.output(eTrace, depth, fmt.Sprint(redactArgs(v)...))
 }
 
 // Traceln logs with the eTrace log_level.
 // Arguments are handled in the manner of fmt.Println.
 func (l *Logger) Traceln(v ...interface{}) {
-	l.output(eTrace, 0, fmt.Sprintln(v...))
+	l.output(eTrace, 0, fmt.Sprintln(redactArgs(v)...))
 }
 
 // Tracef logs with the eTrace log_level.
 // Arguments are handled in the manner of fmt.Printf.
 func (l *Logger) Tracef(format string, v ...interface{}) {
-	l.output(eTrace, 0, fmt.Sprintf(format, v...))
+	l.output(eTrace, 0, fmt.Sprintf(format, redactArgs(v)...))
 }
 
 // Info logs with the Info log_level.
 // Arguments are handled in the manner of fmt.Print.
 func (l *Logger) Info(v ...interface{}) {
-	l.output(eInfo, 0, fmt.Sprint(v...))
+	l.output(eInfo, 0, fmt.Sprint(redactArgs(v)...))
 }
 
 // InfoDepth acts as Info but uses depth to determine which call frame to log.
 // InfoDepth(0, "msg") is the same as Info("msg").
 func (l *Logger) InfoDepth(depth int, v ...interface{}) {
-	l.output(eInfo, depth, fmt.Sprint(v...))
+	l.output(eInfo, depth, fmt.Sprint(redactArgs(v)...))
 }
 
 // Infoln logs with the Info log_level.
 // Arguments are handled in the manner of fmt.Println.
 func (l *Logger) Infoln(v ...interface{}) {
-	l.output(eInfo, 0, fmt.Sprintln(v...))
+	l.output(eInfo, 0, fmt.Sprintln(redactArgs(v)...))
 }
 
 // Infof logs with the Info log_level.
 // Arguments are handled in the manner of fmt.Printf.
 func (l *Logger) Infof(format string, v ...interface{}) {
-	l.output(eInfo, 0, fmt.Sprintf(format, v...))
+	l.output(eInfo, 0, fmt.Sprintf(format, redactArgs(v)...))
 }
 
 // Warning logs with the Warning log_level.
 // Arguments are handled in the manner of fmt.Print.
 func (l *Logger) Warning(v ...interface{}) {
-	l.output(eWarn, 0, fmt.Sprint(v...))
+	l.output(eWarn, 0, fmt.Sprint(redactArgs(v)...))
 }
 
 // WarningDepth acts as Warning but uses depth to determine which call frame to log.
 // WarningDepth(0, "msg") is the same as Warning("msg").
 func (l *Logger) WarningDepth(depth int, v ...interface{}) {
-	l.output(eWarn, depth, fmt.Sprint(v...))
+	l.output(eWarn, depth, fmt.Sprint(redactArgs(v)...))
 }
 
 // Warningln logs with the Warning log_level.
 // Arguments are handled in the manner of fmt.Println.
 func (l *Logger) Warningln(v ...interface{}) {
-	l.output(eWarn, 0, fmt.Sprintln(v...))
+	l.output(eWarn, 0, fmt.Sprintln(redactArgs(v)...))
 }
 
 // Warningf logs with the Warning log_level.
 // Arguments are handled in the manner of fmt.Printf.
 func (l *Logger) Warningf(format string, v ...interface{}) {
-	l.output(eWarn, 0, fmt.Sprintf(format, v...))
+	l.output(eWarn, 0, fmt.Sprintf(format, redactArgs(v)...))
 }
 
 // Error logs with the ERROR log_level.
 // Arguments are handled in the manner of fmt.Print.
 func (l *Logger) Error(v ...interface{}) {
-	l.output(eError, 0, fmt.Sprint(v...))
+	l.output(eError, 0, fmt.Sprint(redactArgs(v)...))
 }
 
 // ErrorDepth acts as Error but uses depth to determine which call frame to log.
 // ErrorDepth(0, "msg") is the same as Error("msg").
 func (l *Logger) ErrorDepth(depth int, v ...interface{}) {
-	l.output(eError, depth, fmt.Sprint(v...))
+	l.output(eError, depth, fmt.Sprint(redactArgs(v)...))
 }
 
 // Errorln logs with the ERROR log_level.
 // Arguments are handled in the manner of fmt.Println.
 func (l *Logger) Errorln(v ...interface{}) {
-	l.output(eError, 0, fmt.Sprintln(v...))
+	l.output(eError, 0, fmt.Sprintln(redactArgs(v)...))
 }
 
 // Errorf logs with the Error log_level.
 // Arguments are handled in the manner of fmt.Printf.
 func (l *Logger) Errorf(format string, v ...interface{}) {
-	l.output(eError, 0, fmt.Sprintf(format, v...))
+	l.output(eError, 0, fmt.Sprintf(format, redactArgs(v)...))
 }
 
-// Fatal logs with the Fatal log_level, and ends with os.Exit(1).
+// Fatal logs with the Fatal log_level, dumps a goroutine stack trace, closes
+// l, runs any hook registered with OnFatal, then exits via the function set
+// with SetExitFunc (os.Exit(1) by default).
 // Arguments are handled in the manner of fmt.Print.
 func (l *Logger) Fatal(v ...interface{}) {
-	l.output(eFatal, 0, fmt.Sprint(v...))
-	l.Close()
-	os.Exit(1)
+	l.fatal(0, fmt.Sprint(redactArgs(v)...))
 }
 
 // FatalDepth acts as Fatal but uses depth to determine which call frame to log.
 // FatalDepth(0, "msg") is the same as Fatal("msg").
 func (l *Logger) FatalDepth(depth int, v ...interface{}) {
-	l.output(eFatal, depth, fmt.Sprint(v...))
-	l.Close()
-	os.Exit(1)
+	l.fatal(depth, fmt.Sprint(redactArgs(v)...))
 }
 
-// Fatalln logs with the Fatal log_level, and ends with os.Exit(1).
+// Fatalln acts as Fatal.
 // Arguments are handled in the manner of fmt.Println.
 func (l *Logger) Fatalln(v ...interface{}) {
-	l.output(eFatal, 0, fmt.Sprintln(v...))
-	l.Close()
-	os.Exit(1)
+	l.fatal(0, fmt.Sprintln(redactArgs(v)...))
 }
 
-// Fatalf logs with the Fatal log_level, and ends with os.Exit(1).
+// Fatalf acts as Fatal.
 // Arguments are handled in the manner of fmt.Printf.
 func (l *Logger) Fatalf(format string, v ...interface{}) {
-	l.output(eFatal, 0, fmt.Sprintf(format, v...))
-	l.Close()
-	os.Exit(1)
-}
-
-// SetFlags sets the output flags for the logger.
-func SetFlags(flag int) {
-	defaultLogger.logTrace.SetFlags(flag)
-	defaultLogger.logInfo.SetFlags(flag)
-	defaultLogger.logWarn.SetFlags(flag)
-	defaultLogger.logError.SetFlags(flag)
-	defaultLogger.logFatal.SetFlags(flag)
+	l.fatal(0, fmt.Sprintf(format, redactArgs(v)...))
 }
 
 // Trace uses the default logger and logs with the eTrace log_level.
 // Arguments are handled in the manner of fmt.Print.
 func Trace(v ...interface{}) {
-	defaultLogger.output(eTrace, 0, fmt.Sprint(v...))
+	defaultLogger.output(eTrace, 0, fmt.Sprint(redactArgs(v)...))
 }
 
 // TraceDepth acts as Trace but uses depth to determine which call frame to log.
 // TraceDepth(0, "msg") is the same as Trace("msg").
 func TraceDepth(depth int, v ...interface{}) {
-	defaultLogger.output(eTrace, depth, fmt.Sprint(v...))
+	defaultLogger.output(eTrace, depth, fmt.Sprint(redactArgs(v)...))
 }
 
 // Traceln uses the default logger and logs with the eTrace log_level.
 // Arguments are handled in the manner of fmt.Println.
 func Traceln(v ...interface{}) {
-	defaultLogger.output(eTrace, 0, fmt.Sprintln(v...))
+	defaultLogger.output(eTrace, 0, fmt.Sprintln(redactArgs(v)...))
 }
 
 // Tracef uses the default logger and logs with the eTrace log_level.
 // Arguments are handled in the manner of fmt.Printf.
 func Tracef(format string, v ...interface{}) {
-	defaultLogger.output(eTrace, 0, fmt.Sprintf(format, v...))
+	defaultLogger.output(eTrace, 0, fmt.Sprintf(format, redactArgs(v)...))
 }
 
 // Info uses the default logger and logs with the Info log_level.
 // Arguments are handled in the manner of fmt.Print.
 func Info(v ...interface{}) {
-	defaultLogger.output(eInfo, 0, fmt.Sprint(v...))
+	defaultLogger.output(eInfo, 0, fmt.Sprint(redactArgs(v)...))
 }
 
 // InfoDepth acts as Info but uses depth to determine which call frame to log.
 // InfoDepth(0, "msg") is the same as Info("msg").
 func InfoDepth(depth int, v ...interface{}) {
-	defaultLogger.output(eInfo, depth, fmt.Sprint(v...))
+	defaultLogger.output(eInfo, depth, fmt.Sprint(redactArgs(v)...))
 }
 
 // Infoln uses the default logger and logs with the Info log_level.
 // Arguments are handled in the manner of fmt.Println.
 func Infoln(v ...interface{}) {
-	defaultLogger.output(eInfo, 0, fmt.Sprintln(v...))
+	defaultLogger.output(eInfo, 0, fmt.Sprintln(redactArgs(v)...))
 }
 
 // Infof uses the default logger and logs with the Info log_level.
 // Arguments are handled in the manner of fmt.Printf.
 func Infof(format string, v ...interface{}) {
-	defaultLogger.output(eInfo, 0, fmt.Sprintf(format, v...))
+	defaultLogger.output(eInfo, 0, fmt.Sprintf(format, redactArgs(v)...))
 }
 
 // Warning uses the default logger and logs with the Warning log_level.
 // Arguments are handled in the manner of fmt.Print.
 func Warning(v ...interface{}) {
-	defaultLogger.output(eWarn, 0, fmt.Sprint(v...))
+	defaultLogger.output(eWarn, 0, fmt.Sprint(redactArgs(v)...))
 }
 
 // WarningDepth acts as Warning but uses depth to determine which call frame to log.
 // WarningDepth(0, "msg") is the same as Warning("msg").
 func WarningDepth(depth int, v ...interface{}) {
-	defaultLogger.output(eWarn, depth, fmt.Sprint(v...))
+	defaultLogger.output(eWarn, depth, fmt.Sprint(redactArgs(v)...))
 }
 
 // Warningln uses the default logger and logs with the Warning log_level.
 // Arguments are handled in the manner of fmt.Println.
 func Warningln(v ...interface{}) {
-	defaultLogger.output(eWarn, 0, fmt.Sprintln(v...))
+	defaultLogger.output(eWarn, 0, fmt.Sprintln(redactArgs(v)...))
 }
 
 // Warningf uses the default logger and logs with the Warning log_level.
 // Arguments are handled in the manner of fmt.Printf.
 func Warningf(format string, v ...interface{}) {
-	defaultLogger.output(eWarn, 0, fmt.Sprintf(format, v...))
+	defaultLogger.output(eWarn, 0, fmt.Sprintf(format, redactArgs(v)...))
 }
 
 // Error uses the default logger and logs with the Error log_level.
 // Arguments are handled in the manner of fmt.Print.
 func Error(v ...interface{}) {
-	defaultLogger.output(eError, 0, fmt.Sprint(v...))
+	defaultLogger.output(eError, 0, fmt.Sprint(redactArgs(v)...))
 }
 
 // ErrorDepth acts as Error but uses depth to determine which call frame to log.
 // ErrorDepth(0, "msg") is the same as Error("msg").
 func ErrorDepth(depth int, v ...interface{}) {
-	defaultLogger.output(eError, depth, fmt.Sprint(v...))
+	defaultLogger.output(eError, depth, fmt.Sprint(redactArgs(v)...))
 }
 
 // Errorln uses the default logger and logs with the Error log_level.
 // Arguments are handled in the manner of fmt.Println.
 func Errorln(v ...interface{}) {
-	defaultLogger.output(eError, 0, fmt.Sprintln(v...))
+	defaultLogger.output(eError, 0, fmt.Sprintln(redactArgs(v)...))
 }
 
 // Errorf uses the default logger and logs with the Error log_level.
 // Arguments are handled in the manner of fmt.Printf.
 func Errorf(format string, v ...interface{}) {
-	defaultLogger.output(eError, 0, fmt.Sprintf(format, v...))
+	defaultLogger.output(eError, 0, fmt.Sprintf(format, redactArgs(v)...))
 }
 
-// Fatalln uses the default logger, logs with the Fatal log_level,
-// and ends with os.Exit(1).
+// Fatal uses the default logger; see Logger.Fatal.
 // Arguments are handled in the manner of fmt.Print.
 func Fatal(v ...interface{}) {
-	defaultLogger.output(eFatal, 0, fmt.Sprint(v...))
-	defaultLogger.Close()
-	os.Exit(1)
+	defaultLogger.fatal(0, fmt.Sprint(redactArgs(v)...))
 }
 
 // FatalDepth acts as Fatal but uses depth to determine which call frame to log.
 // FatalDepth(0, "msg") is the same as Fatal("msg").
 func FatalDepth(depth int, v ...interface{}) {
-	defaultLogger.output(eFatal, depth, fmt.Sprint(v...))
-	defaultLogger.Close()
-	os.Exit(1)
+	defaultLogger.fatal(depth, fmt.Sprint(redactArgs(v)...))
 }
 
-// Fatalln uses the default logger, logs with the Fatal log_level,
-// and ends with os.Exit(1).
+// Fatalln uses the default logger; see Logger.Fatal.
 // Arguments are handled in the manner of fmt.Println.
 func Fatalln(v ...interface{}) {
-	defaultLogger.output(eFatal, 0, fmt.Sprintln(v...))
-	defaultLogger.Close()
-	os.Exit(1)
+	defaultLogger.fatal(0, fmt.Sprintln(redactArgs(v)...))
 }
 
-// Fatalf uses the default logger, logs with the Fatal log_level,
-// and ends with os.Exit(1).
+// Fatalf uses the default logger; see Logger.Fatal.
 // Arguments are handled in the manner of fmt.Printf.
 func Fatalf(format string, v ...interface{}) {
-	defaultLogger.output(eFatal, 0, fmt.Sprintf(format, v...))
-	defaultLogger.Close()
-	os.Exit(1)
+	defaultLogger.fatal(0, fmt.Sprintf(format, redactArgs(v)...))
 }
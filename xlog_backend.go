@@ -0,0 +1,179 @@
+package xlog
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const flags = log.Ldate | log.Lmicroseconds | log.Lshortfile
+
+// Record is a single log entry handed to a Backend's Write method.
+type Record struct {
+	Level   Level
+	Time    time.Time
+	File    string
+	Line    int
+	Message string // always newline-terminated
+
+	// EventID is set by InfoID/WarningID/ErrorID and is zero otherwise. Only
+	// the Windows Event Log backend consults it; other backends ignore it.
+	EventID uint32
+}
+
+// Backend receives the Records a Logger routes to it. Implementations must
+// be safe for concurrent use; Write may be called from multiple goroutines
+// logging at once.
+type Backend interface {
+	Write(Record) error
+	Close() error
+}
+
+type backendEntry struct {
+	name     string
+	minLevel Level
+	backend  Backend
+}
+
+// AddBackend attaches b under name so it receives every Record at minLevel
+// or more severe. If name is already registered, its entry is replaced; the
+// previous Backend is left open, so callers that want it closed should
+// RemoveBackend it first. AddBackend may be called at any time, including
+// to reconfigure a running Logger on SIGHUP.
+func (l *Logger) AddBackend(name string, minLevel Level, b Backend) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.addBackendLocked(name, minLevel, b)
+}
+
+// addBackend is AddBackend without the exported name, for use by Init and
+// initialize before l is reachable from other goroutines.
+func (l *Logger) addBackend(name string, minLevel Level, b Backend) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.addBackendLocked(name, minLevel, b)
+}
+
+func (l *Logger) addBackendLocked(name string, minLevel Level, b Backend) {
+	for i, e := range l.backends {
+		if e.name == name {
+			l.backends[i] = backendEntry{name: name, minLevel: minLevel, backend: b}
+			return
+		}
+	}
+	l.backends = append(l.backends, backendEntry{name: name, minLevel: minLevel, backend: b})
+}
+
+// RemoveBackend closes and detaches the backend registered under name, if
+// any.
+func (l *Logger) RemoveBackend(name string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i, e := range l.backends {
+		if e.name == name {
+			if err := e.backend.Close(); err != nil {
+				fmt.Fprintf(os.Stderr, "xlog: closing backend %q: %v\n", name, err)
+			}
+			l.backends = append(l.backends[:i], l.backends[i+1:]...)
+			return
+		}
+	}
+}
+
+// SetLevel changes the minimum severity routed to the backend registered
+// under name. It is a no-op if name is not registered.
+func (l *Logger) SetLevel(name string, lvl Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i, e := range l.backends {
+		if e.name == name {
+			l.backends[i].minLevel = lvl
+			return
+		}
+	}
+}
+
+// writerBackend is the built-in Backend behind Init's "file" and "stderr"
+// entries. It formats Records the way xlog always has: a severity tag
+// followed by a log-package-style date/time/file header.
+type writerBackend struct {
+	mu     sync.Mutex
+	w      io.Writer
+	prefix string
+	flags  int32
+}
+
+func newWriterBackend(w io.Writer, prefix string) *writerBackend {
+	return &writerBackend{w: w, prefix: prefix, flags: int32(flags)}
+}
+
+// SetFlags sets the log-package-style output flags (e.g. log.Lshortfile)
+// used to format each Record.
+func (b *writerBackend) SetFlags(flag int) {
+	atomic.StoreInt32(&b.flags, int32(flag))
+}
+
+func (b *writerBackend) Write(r Record) error {
+	f := int(atomic.LoadInt32(&b.flags))
+
+	var buf strings.Builder
+	buf.WriteString(b.prefix)
+	buf.WriteString(tagFor(r.Level))
+	if f&(log.Ldate|log.Ltime|log.Lmicroseconds) != 0 {
+		if f&log.Ldate != 0 {
+			y, mo, d := r.Time.Date()
+			fmt.Fprintf(&buf, "%04d/%02d/%02d ", y, mo, d)
+		}
+		if f&(log.Ltime|log.Lmicroseconds) != 0 {
+			h, mi, s := r.Time.Clock()
+			fmt.Fprintf(&buf, "%02d:%02d:%02d", h, mi, s)
+			if f&log.Lmicroseconds != 0 {
+				fmt.Fprintf(&buf, ".%06d", r.Time.Nanosecond()/1e3)
+			}
+			buf.WriteString(" ")
+		}
+	}
+	if f&(log.Lshortfile|log.Llongfile) != 0 {
+		file := r.File
+		if f&log.Lshortfile != 0 {
+			file = filepath.Base(file)
+		}
+		fmt.Fprintf(&buf, "%s:%d: ", file, r.Line)
+	}
+	buf.WriteString(r.Message)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	_, err := io.WriteString(b.w, buf.String())
+	return err
+}
+
+func (b *writerBackend) Close() error {
+	if c, ok := b.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+func tagFor(l Level) string {
+	switch l {
+	case eFatal:
+		return tagFatal
+	case eError:
+		return tagError
+	case eWarn:
+		return tagWarn
+	case eInfo:
+		return tagInfo
+	case eTrace:
+		return tagTrace
+	default:
+		return ""
+	}
+}
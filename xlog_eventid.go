@@ -0,0 +1,38 @@
+package xlog
+
+import "fmt"
+
+// InfoID logs with the Info log_level, threading id down to any backend
+// that keys log entries off a per-record ID. Currently only the Windows
+// Event Log backend that Init adds when systemLog is true does anything
+// with id; it is ignored elsewhere, including by Init's default Linux
+// backends.
+// Arguments are handled in the manner of fmt.Print.
+func (l *Logger) InfoID(id uint32, v ...interface{}) {
+	l.outputID(eInfo, 0, id, fmt.Sprint(redactArgs(v)...))
+}
+
+// WarningID acts like InfoID but logs with the Warning log_level.
+func (l *Logger) WarningID(id uint32, v ...interface{}) {
+	l.outputID(eWarn, 0, id, fmt.Sprint(redactArgs(v)...))
+}
+
+// ErrorID acts like InfoID but logs with the Error log_level.
+func (l *Logger) ErrorID(id uint32, v ...interface{}) {
+	l.outputID(eError, 0, id, fmt.Sprint(redactArgs(v)...))
+}
+
+// InfoID uses the default logger; see Logger.InfoID.
+func InfoID(id uint32, v ...interface{}) {
+	defaultLogger.outputID(eInfo, 0, id, fmt.Sprint(redactArgs(v)...))
+}
+
+// WarningID uses the default logger; see Logger.WarningID.
+func WarningID(id uint32, v ...interface{}) {
+	defaultLogger.outputID(eWarn, 0, id, fmt.Sprint(redactArgs(v)...))
+}
+
+// ErrorID uses the default logger; see Logger.ErrorID.
+func ErrorID(id uint32, v ...interface{}) {
+	defaultLogger.outputID(eError, 0, id, fmt.Sprint(redactArgs(v)...))
+}
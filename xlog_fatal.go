@@ -0,0 +1,77 @@
+package xlog
+
+import (
+	"os"
+	"runtime"
+)
+
+// OnFatal registers fn to run after l is closed but before the process
+// exits, on every subsequent Fatal call. A later call replaces any
+// previously registered hook. fn runs synchronously in the goroutine that
+// called Fatal.
+func (l *Logger) OnFatal(fn func()) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.onFatal = fn
+}
+
+// SetExitFunc overrides the function Fatal uses to terminate the process,
+// in place of os.Exit. Tests can use this to observe a Fatal call without
+// actually exiting.
+func (l *Logger) SetExitFunc(fn func(int)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.exitFunc = fn
+}
+
+// SetFatalStackAll controls whether Fatal's goroutine stack dump includes
+// every goroutine (true) or only the one that called Fatal (false, the
+// default).
+func (l *Logger) SetFatalStackAll(all bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.stackAll = all
+}
+
+// fatal is the shared implementation behind Fatal, FatalDepth, Fatalln and
+// Fatalf: it logs txt at the Fatal log_level, dumps a goroutine stack trace
+// to the error-severity backends, closes l, runs any hook registered with
+// OnFatal, then exits via the function registered with SetExitFunc.
+func (l *Logger) fatal(depth int, txt string) {
+	l.output(eFatal, depth+1, txt)
+	l.dumpStack(depth + 2)
+	l.Close()
+
+	l.mu.RLock()
+	onFatal, exitFunc := l.onFatal, l.exitFunc
+	l.mu.RUnlock()
+
+	if onFatal != nil {
+		onFatal()
+	}
+	if exitFunc == nil {
+		exitFunc = os.Exit
+	}
+	exitFunc(1)
+}
+
+// dumpStack writes a goroutine stack trace to every backend that accepts
+// Error-severity records, for post-mortem debugging of the crash. It dumps
+// every goroutine when SetFatalStackAll(true) was called, otherwise just
+// the one calling Fatal.
+func (l *Logger) dumpStack(depth int) {
+	l.mu.RLock()
+	all := l.stackAll
+	l.mu.RUnlock()
+
+	buf := make([]byte, 1<<16)
+	for {
+		n := runtime.Stack(buf, all)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+	l.output(eError, depth, "goroutine stack dump:\n"+string(buf))
+}
@@ -0,0 +1,93 @@
+package xlog
+
+import (
+	"strings"
+	"sync"
+)
+
+// Redactor is implemented by values that must never be logged verbatim. Any
+// logging argument implementing Redactor is replaced by the result of
+// Redacted() before it reaches fmt.
+type Redactor interface {
+	Redacted() interface{}
+}
+
+// Redact returns a string of the same length as s, with every character
+// replaced by '*'. It is meant for building a Redacted() implementation,
+// e.g. `func (c creds) Redacted() interface{} { return xlog.Redact(c.Password) }`.
+func Redact(s string) string {
+	return strings.Repeat("*", len(s))
+}
+
+// Secret wraps a value of type T so it can be passed around and still be
+// logged safely: both its Redacted (for the Redactor-aware call sites) and
+// String (for everywhere else, e.g. nested in a %v of another value) forms
+// hide the wrapped value.
+type Secret[T any] struct {
+	value T
+}
+
+// NewSecret wraps value as a Secret.
+func NewSecret[T any](value T) Secret[T] {
+	return Secret[T]{value: value}
+}
+
+// Unwrap returns the wrapped value.
+func (s Secret[T]) Unwrap() T {
+	return s.value
+}
+
+// Redacted implements Redactor.
+func (s Secret[T]) Redacted() interface{} {
+	return "***"
+}
+
+// String implements fmt.Stringer.
+func (s Secret[T]) String() string {
+	return "***"
+}
+
+// redactArgs returns v with every element implementing Redactor replaced by
+// its Redacted() value. It returns v itself, unmodified, when nothing needs
+// redacting.
+func redactArgs(v []interface{}) []interface{} {
+	var out []interface{}
+	for i, a := range v {
+		if r, ok := a.(Redactor); ok {
+			if out == nil {
+				out = append([]interface{}{}, v[:i]...)
+			}
+			out = append(out, r.Redacted())
+		} else if out != nil {
+			out = append(out, a)
+		}
+	}
+	if out == nil {
+		return v
+	}
+	return out
+}
+
+var (
+	sensitiveKeysMu sync.RWMutex
+	sensitiveKeys   = map[string]bool{
+		"password":      true,
+		"token":         true,
+		"authorization": true,
+	}
+)
+
+// RegisterSensitiveKey marks name (matched case-insensitively) as a
+// sensitive field name. Any WithValues/InfoS/ErrorS key/value pair whose
+// key matches has its value redacted with Redact before formatting.
+func RegisterSensitiveKey(name string) {
+	sensitiveKeysMu.Lock()
+	defer sensitiveKeysMu.Unlock()
+	sensitiveKeys[strings.ToLower(name)] = true
+}
+
+func isSensitiveKey(name string) bool {
+	sensitiveKeysMu.RLock()
+	defer sensitiveKeysMu.RUnlock()
+	return sensitiveKeys[strings.ToLower(name)]
+}
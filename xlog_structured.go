@@ -0,0 +1,132 @@
+package xlog
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-logr/logr"
+)
+
+// WithValues returns a new Logger that, on every subsequent InfoS or ErrorS
+// call, logs keysAndValues ahead of the call's own pairs in addition to any
+// inherited from earlier WithValues calls. l itself is left unmodified.
+func (l *Logger) WithValues(keysAndValues ...interface{}) *Logger {
+	n := l.clone()
+	n.values = append(append([]interface{}{}, l.values...), keysAndValues...)
+	return n
+}
+
+// WithName returns a new Logger whose name is name appended to l's existing
+// name, dot-separated. The dotted name is shown ahead of the message on
+// every InfoS and ErrorS record logged through the returned Logger; the
+// fmt.Print-style methods do not show it. l itself is left unmodified.
+func (l *Logger) WithName(name string) *Logger {
+	n := l.clone()
+	if l.name == "" {
+		n.name = name
+	} else {
+		n.name = l.name + "." + name
+	}
+	return n
+}
+
+// InfoS logs msg at the Info log_level, followed by any values attached with
+// WithValues and then keysAndValues, formatted as logfmt key=value pairs.
+func (l *Logger) InfoS(msg string, keysAndValues ...interface{}) {
+	l.output(eInfo, 0, l.formatStructured(msg, keysAndValues))
+}
+
+// ErrorS logs msg at the Error log_level, followed by any values attached
+// with WithValues, then an "error" key holding err.Error() (omitted when err
+// is nil), then keysAndValues, formatted as logfmt key=value pairs.
+func (l *Logger) ErrorS(err error, msg string, keysAndValues ...interface{}) {
+	if err != nil {
+		keysAndValues = append([]interface{}{"error", err.Error()}, keysAndValues...)
+	}
+	l.output(eError, 0, l.formatStructured(msg, keysAndValues))
+}
+
+// formatStructured builds the text for InfoS/ErrorS: the logger's dotted
+// name (if any), the message, then the persisted and call-site key/value
+// pairs in that order.
+func (l *Logger) formatStructured(msg string, keysAndValues []interface{}) string {
+	var b strings.Builder
+	if l.name != "" {
+		b.WriteString(l.name)
+		b.WriteString(": ")
+	}
+	b.WriteString(msg)
+	writeLogfmt(&b, l.values)
+	writeLogfmt(&b, keysAndValues)
+	return b.String()
+}
+
+// writeLogfmt appends each key=value pair in kv to b, quoting values per
+// logfmt where needed. A trailing key without a matching value is dropped.
+// A value implementing Redactor is substituted with its Redacted() return,
+// and any value whose key is registered with RegisterSensitiveKey is
+// replaced with Redact(fmt.Sprint(value)).
+func writeLogfmt(b *strings.Builder, kv []interface{}) {
+	for i := 0; i+1 < len(kv); i += 2 {
+		key, ok := kv[i].(string)
+		if !ok {
+			key = fmt.Sprint(kv[i])
+		}
+		value := kv[i+1]
+		if r, ok := value.(Redactor); ok {
+			value = r.Redacted()
+		} else if isSensitiveKey(key) {
+			value = Redact(fmt.Sprint(value))
+		}
+		b.WriteByte(' ')
+		b.WriteString(key)
+		b.WriteByte('=')
+		b.WriteString(logfmtValue(value))
+	}
+}
+
+// logfmtValue renders v the way fmt.Sprint would, quoting the result if it
+// is empty or contains a space, '=' or '"'.
+func logfmtValue(v interface{}) string {
+	s := fmt.Sprint(v)
+	if s == "" || strings.ContainsAny(s, " =\"") {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// sink adapts a *Logger to the logr.LogSink interface so xlog can be
+// plugged into libraries written against logr.
+type sink struct {
+	logger *Logger
+}
+
+// Sink returns a logr.LogSink backed by l, so xlog can be plugged into
+// libraries that expect a logr backend without disturbing the existing
+// fmt.Print-style calls on l.
+func (l *Logger) Sink() logr.LogSink {
+	return &sink{logger: l}
+}
+
+func (s *sink) Init(info logr.RuntimeInfo) {}
+
+func (s *sink) Enabled(level int) bool {
+	return verbosityEnabled(level, 1)
+}
+
+func (s *sink) Info(level int, msg string, keysAndValues ...interface{}) {
+	s.logger.InfoS(msg, keysAndValues...)
+}
+
+func (s *sink) Error(err error, msg string, keysAndValues ...interface{}) {
+	s.logger.ErrorS(err, msg, keysAndValues...)
+}
+
+func (s *sink) WithValues(keysAndValues ...interface{}) logr.LogSink {
+	return &sink{logger: s.logger.WithValues(keysAndValues...)}
+}
+
+func (s *sink) WithName(name string) logr.LogSink {
+	return &sink{logger: s.logger.WithName(name)}
+}
@@ -1,22 +1,50 @@
+//go:build !windows
+
 package xlog
 
 import (
 	"log/syslog"
 )
 
-func setup(src string) (*syslog.Writer, *syslog.Writer, *syslog.Writer, error) {
+// syslogBackend is the Backend behind Init's "syslog" entry on Linux.
+type syslogBackend struct {
+	errW, warnW, infoW *syslog.Writer
+}
+
+func newSystemBackend(src string, opts InitOptions) (Backend, error) {
 	const facility = syslog.LOG_USER
-	il, err := syslog.New(facility|syslog.LOG_NOTICE, src)
+	infoW, err := syslog.New(facility|syslog.LOG_NOTICE, src)
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, err
 	}
-	wl, err := syslog.New(facility|syslog.LOG_WARNING, src)
+	warnW, err := syslog.New(facility|syslog.LOG_WARNING, src)
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, err
 	}
-	el, err := syslog.New(facility|syslog.LOG_ERR, src)
+	errW, err := syslog.New(facility|syslog.LOG_ERR, src)
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, err
+	}
+	return &syslogBackend{errW: errW, warnW: warnW, infoW: infoW}, nil
+}
+
+func (b *syslogBackend) Write(r Record) error {
+	switch r.Level {
+	case eFatal, eError:
+		return b.errW.Err(r.Message)
+	case eWarn:
+		return b.warnW.Warning(r.Message)
+	default:
+		return b.infoW.Info(r.Message)
+	}
+}
+
+func (b *syslogBackend) Close() error {
+	var firstErr error
+	for _, w := range []*syslog.Writer{b.errW, b.warnW, b.infoW} {
+		if err := w.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
 	}
-	return il, wl, el, nil
+	return firstErr
 }
@@ -0,0 +1,165 @@
+package xlog
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Verbose is returned by V and the Logger.V family. Its Info, Infoln and
+// Infof methods are no-ops unless the verbosity level passed to V was
+// enabled, either by the global threshold set with SetVerbosity or by a
+// per-file override registered with SetVModule.
+type Verbose struct {
+	enabled bool
+	logger  *Logger
+}
+
+var (
+	verbosity int32 // global threshold, set via SetVerbosity
+
+	vmodule atomic.Value // holds *vModuleFilter
+	vCache  atomic.Value // holds *sync.Map, PC -> resolved level
+)
+
+func init() {
+	vCache.Store(&sync.Map{})
+}
+
+// SetVerbosity sets the global verbosity threshold consulted by V. A call
+// V(n) is enabled when n is less than or equal to the threshold, unless
+// overridden for the calling file by SetVModule.
+func SetVerbosity(level int) {
+	atomic.StoreInt32(&verbosity, int32(level))
+	vCache.Store(&sync.Map{})
+}
+
+type modulePattern struct {
+	pattern string
+	literal bool
+	level   int
+}
+
+type vModuleFilter struct {
+	patterns []modulePattern
+}
+
+// level returns the verbosity level registered for the source file at path,
+// and whether any pattern matched it.
+func (f *vModuleFilter) level(path string) (int, bool) {
+	if f == nil {
+		return 0, false
+	}
+	base := strings.TrimSuffix(filepath.Base(path), ".go")
+	for _, p := range f.patterns {
+		if p.literal {
+			if p.pattern == base {
+				return p.level, true
+			}
+			continue
+		}
+		if ok, _ := filepath.Match(p.pattern, base); ok {
+			return p.level, true
+		}
+	}
+	return 0, false
+}
+
+// SetVModule sets per-file verbosity overrides from a glog/klog-style
+// "-vmodule" spec: a comma-separated list of pattern=level pairs, e.g.
+// "gopher=3,embed*=2". A pattern is matched against the base name of the
+// calling source file, without its ".go" suffix, and may use the * and ?
+// wildcards described by path.Match. SetVModule replaces any previously
+// registered overrides.
+func SetVModule(spec string) error {
+	var patterns []modulePattern
+	for _, entry := range strings.Split(spec, ",") {
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("xlog: invalid vmodule entry %q", entry)
+		}
+		level, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return fmt.Errorf("xlog: invalid vmodule level in %q: %v", entry, err)
+		}
+		pattern := parts[0]
+		patterns = append(patterns, modulePattern{
+			pattern: pattern,
+			literal: !strings.ContainsAny(pattern, "*?"),
+			level:   level,
+		})
+	}
+	vmodule.Store(&vModuleFilter{patterns: patterns})
+	vCache.Store(&sync.Map{})
+	return nil
+}
+
+// verbosityEnabled reports whether level is enabled for the file that called
+// V, skip frames up from its own caller. The resolved level is cached by
+// program counter so repeat calls on a hot path skip the vmodule lookup.
+func verbosityEnabled(level, skip int) bool {
+	pc, file, _, ok := runtime.Caller(skip + 1)
+	if !ok {
+		return level <= int(atomic.LoadInt32(&verbosity))
+	}
+
+	cache := vCache.Load().(*sync.Map)
+	if cached, ok := cache.Load(pc); ok {
+		return level <= cached.(int)
+	}
+
+	threshold := int(atomic.LoadInt32(&verbosity))
+	if f, _ := vmodule.Load().(*vModuleFilter); f != nil {
+		if lvl, matched := f.level(file); matched {
+			threshold = lvl
+		}
+	}
+	cache.Store(pc, threshold)
+	return level <= threshold
+}
+
+// V reports whether verbosity level level is enabled for the calling source
+// file and returns a Verbose bound to the default logger. Arguments passed
+// to the Verbose methods are evaluated unconditionally by the caller, so
+// expensive arguments should still be guarded by an explicit if V(n) check
+// when appropriate.
+func V(level int) Verbose {
+	return Verbose{enabled: verbosityEnabled(level, 1), logger: defaultLogger}
+}
+
+// V acts like the package-level V but the returned Verbose logs through l
+// when enabled.
+func (l *Logger) V(level int) Verbose {
+	return Verbose{enabled: verbosityEnabled(level, 1), logger: l}
+}
+
+// Info logs with the Info log_level if v is enabled.
+// Arguments are handled in the manner of fmt.Print.
+func (v Verbose) Info(args ...interface{}) {
+	if v.enabled {
+		v.logger.output(eInfo, 0, fmt.Sprint(redactArgs(args)...))
+	}
+}
+
+// Infoln logs with the Info log_level if v is enabled.
+// Arguments are handled in the manner of fmt.Println.
+func (v Verbose) Infoln(args ...interface{}) {
+	if v.enabled {
+		v.logger.output(eInfo, 0, fmt.Sprintln(redactArgs(args)...))
+	}
+}
+
+// Infof logs with the Info log_level if v is enabled.
+// Arguments are handled in the manner of fmt.Printf.
+func (v Verbose) Infof(format string, args ...interface{}) {
+	if v.enabled {
+		v.logger.output(eInfo, 0, fmt.Sprintf(format, redactArgs(args)...))
+	}
+}
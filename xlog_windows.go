@@ -1,68 +1,77 @@
 package xlog
 
 import (
-	"fmt"
 	"strings"
 
 	"github.com/golang/sys/windows"
 	"github.com/golang/sys/windows/svc/eventlog"
 )
 
-type writer struct {
-	pri severity
+// eventlogBackend is the Backend behind Init's "syslog" entry on Windows.
+type eventlogBackend struct {
 	src string
 	el  *eventlog.Log
 }
 
-// Write sends a log message to the Event Log.
-func (w *writer) Write(b []byte) (int, error) {
-	switch w.pri {
-	case sInfo:
-		return len(b), w.el.Info(1, string(b))
-	case sWarning:
-		return len(b), w.el.Warning(3, string(b))
-	case sError:
-		return len(b), w.el.Error(2, string(b))
+func newSystemBackend(src string, opts InitOptions) (Backend, error) {
+	var err error
+	if opts.EventMessageFile != "" {
+		err = eventlog.Install(src, opts.EventMessageFile, false, eventlog.Info|eventlog.Warning|eventlog.Error)
+	} else {
+		err = eventlog.InstallAsEventCreate(src, eventlog.Info|eventlog.Warning|eventlog.Error)
 	}
-	return 0, fmt.Errorf("unrecognized severity: %v", w.pri)
-}
-
-func (w *writer) Close() error {
-	return w.el.Close()
-}
-
-func newW(pri severity, src string) (*writer, error) {
 	// Continue if we receive "registry key already exists" or if we get
 	// ERROR_ACCESS_DENIED so that we can log without administrative permissions
 	// for pre-existing eventlog sources.
-	if err := eventlog.InstallAsEventCreate(src, eventlog.Info|eventlog.Warning|eventlog.Error); err != nil {
-		if !strings.Contains(err.Error(), "registry key already exists") && err != windows.ERROR_ACCESS_DENIED {
-			return nil, err
-		}
+	if err != nil && !strings.Contains(err.Error(), "registry key already exists") && err != windows.ERROR_ACCESS_DENIED {
+		return nil, err
 	}
 	el, err := eventlog.Open(src)
 	if err != nil {
 		return nil, err
 	}
-	return &writer{
-		pri: pri,
-		src: src,
-		el:  el,
-	}, nil
+	return &eventlogBackend{src: src, el: el}, nil
 }
 
-func setup(src string) (*writer, *writer, *writer, error) {
-	infoL, err := newW(sInfo, src)
-	if err != nil {
-		return nil, nil, nil, err
+// RegisterSource installs name as a Windows Event Log source using msgFile
+// as its message-resource DLL, in place of the generic EventCreate message
+// file used when InitOptions.EventMessageFile is left empty. This lets
+// Event Viewer show a real description for the ids the caller intends to
+// pass to InfoID/WarningID/ErrorID for this source, rather than "The
+// description for Event ID cannot be found". ids is for documentation only
+// and is not validated against msgFile.
+func RegisterSource(name, msgFile string, ids ...uint32) error {
+	return eventlog.Install(name, msgFile, false, eventlog.Info|eventlog.Warning|eventlog.Error)
+}
+
+// eventID returns id if the caller supplied one via InfoID/WarningID/
+// ErrorID, otherwise the hardcoded ID xlog has always used for level.
+func eventID(level Level, id uint32) uint32 {
+	if id != 0 {
+		return id
 	}
-	warningL, err := newW(sWarning, src)
-	if err != nil {
-		return nil, nil, nil, err
+	switch level {
+	case eFatal, eError:
+		return 2
+	case eWarn:
+		return 3
+	default:
+		return 1
 	}
-	errL, err := newW(sError, src)
-	if err != nil {
-		return nil, nil, nil, err
+}
+
+func (b *eventlogBackend) Write(r Record) error {
+	id := eventID(r.Level, r.EventID)
+	switch r.Level {
+	case eFatal, eError:
+		return b.el.Error(id, r.Message)
+	case eWarn:
+		return b.el.Warning(id, r.Message)
+	default:
+		return b.el.Info(id, r.Message)
 	}
-	return infoL, warningL, errL, nil
+}
+
+func (b *eventlogBackend) Close() error {
+	return b.el.Close()
 }